@@ -0,0 +1,109 @@
+// Copyright 2017 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package data
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+// defaultRemoteCacheDir and defaultRemoteCacheTTL are the fallback values for the data.remote
+// caching used by getRemote, overridable site-wide via data.remote.cacheDir/cacheTTL or per
+// [[data.remote]] entry via cacheTTL.
+const (
+	defaultRemoteCacheDir = "resources/_gen/data_remote"
+	defaultRemoteCacheTTL = time.Hour
+)
+
+// remoteCacheEntry is what getRemote persists to disk for each fetched URL: the body, the
+// response headers needed to make a conditional GET (ETag, Last-Modified), and when it was
+// fetched, so a TTL can be enforced without another round trip.
+type remoteCacheEntry struct {
+	Body      []byte      `json:"body"`
+	Header    http.Header `json:"header"`
+	FetchedAt time.Time   `json:"fetchedAt"`
+}
+
+// cacheDir returns the directory getRemote persists conditional-GET cache entries under,
+// configurable via data.remote.cacheDir.
+func (ns *Namespace) cacheDir() string {
+	if d := ns.deps.Cfg.GetString("data.remote.cacheDir"); d != "" {
+		return d
+	}
+	return defaultRemoteCacheDir
+}
+
+// cacheKey turns url and the request's negotiated Accept header into a filesystem-safe cache file
+// name. Accept is part of the key, not just url, because the same extensionless URL can be fetched
+// by GetCSV, GetJSON, GetXML etc. with different Accept headers and thus different expected
+// formats; hashing url alone would let one format's cached bytes be served back as another's.
+func cacheKey(url, accept string) string {
+	sum := sha256.Sum256([]byte(accept + "\n" + url))
+	return hex.EncodeToString(sum[:])
+}
+
+// readRemoteCache loads the cache entry for url as last fetched with the given Accept header, if
+// any.
+func (ns *Namespace) readRemoteCache(url, accept string) (*remoteCacheEntry, bool) {
+	b, err := afero.ReadFile(ns.deps.Fs.Source, filepath.Join(ns.cacheDir(), cacheKey(url, accept)))
+	if err != nil {
+		return nil, false
+	}
+
+	var e remoteCacheEntry
+	if err := json.Unmarshal(b, &e); err != nil {
+		return nil, false
+	}
+	return &e, true
+}
+
+// writeRemoteCache persists e for url and accept, overwriting any existing entry.
+func (ns *Namespace) writeRemoteCache(url, accept string, e *remoteCacheEntry) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	dir := ns.cacheDir()
+	if err := ns.deps.Fs.Source.MkdirAll(dir, 0o777); err != nil {
+		return err
+	}
+	return afero.WriteFile(ns.deps.Fs.Source, filepath.Join(dir, cacheKey(url, accept)), b, 0o666)
+}
+
+// invalidateRemoteCache removes the cache entry for url and accept, if any. It is called alongside
+// the legacy deleteCache whenever a retry loop decides a fetched resource was bad (e.g. it failed
+// to parse): without this, getRemote's own TTL would keep serving the just-written, just-failed
+// body back to the very next iteration of that same retry loop instead of making a new request.
+func (ns *Namespace) invalidateRemoteCache(url, accept string) {
+	_ = ns.deps.Fs.Source.Remove(filepath.Join(ns.cacheDir(), cacheKey(url, accept)))
+}
+
+// cacheTTL returns the freshness window for url: the matching [[data.remote]] entry's cacheTTL if
+// set, else data.remote.cacheTTL, else defaultRemoteCacheTTL.
+func (ns *Namespace) cacheTTL(url string) time.Duration {
+	if rc := ns.configFor(url); rc != nil && rc.CacheTTL > 0 {
+		return rc.CacheTTL
+	}
+	if d := ns.deps.Cfg.GetDuration("data.remote.cacheTTL"); d > 0 {
+		return d
+	}
+	return defaultRemoteCacheTTL
+}
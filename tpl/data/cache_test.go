@@ -0,0 +1,119 @@
+// Copyright 2017 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package data
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRemoteCacheServesFreshEntryWithoutARequest(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.Header().Set("Etag", `"v1"`)
+		w.Write([]byte(`{"n": 1}`))
+	}))
+	defer srv.Close()
+
+	ns := newTestNamespace(t, nil)
+
+	_, err := ns.GetJSON(srv.URL)
+	require.NoError(t, err)
+	_, err = ns.GetJSON(srv.URL)
+	require.NoError(t, err)
+
+	assert.EqualValues(t, 1, atomic.LoadInt32(&hits), "second call within cacheTTL should be served from cache")
+}
+
+func TestGetRemoteCacheRevalidatesOnETagAfterTTLExpiry(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Etag", `"v1"`)
+		w.Write([]byte(`{"n": 1}`))
+	}))
+	defer srv.Close()
+
+	ns := newTestNamespace(t, func(cfg *viper.Viper) {
+		cfg.Set("data.remote.cacheTTL", "1ms")
+	})
+
+	_, err := ns.GetJSON(srv.URL)
+	require.NoError(t, err)
+
+	time.Sleep(5 * time.Millisecond)
+
+	v, err := ns.GetJSON(srv.URL)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, atomic.LoadInt32(&hits), "stale entry should be revalidated, not silently reused")
+	assert.Equal(t, map[string]interface{}{"n": float64(1)}, v)
+}
+
+func TestGetRemoteRetryAfterParseFailureInvalidatesCache(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&hits, 1)
+		if n == 1 {
+			// A corrupt first response that fails to parse as JSON.
+			w.Write([]byte(`{not valid json`))
+			return
+		}
+		w.Write([]byte(`{"n": 1}`))
+	}))
+	defer srv.Close()
+
+	ns := newTestNamespace(t, func(cfg *viper.Viper) {
+		cfg.Set("data.retries", 1)
+		cfg.Set("data.retrySleep", "1ms")
+	})
+
+	v, err := ns.GetJSON(srv.URL)
+	require.NoError(t, err, "retry must re-fetch instead of replaying the cached bad bytes")
+	assert.Equal(t, map[string]interface{}{"n": float64(1)}, v)
+	assert.EqualValues(t, 2, atomic.LoadInt32(&hits), "the retry must have made a second request")
+}
+
+func TestGetRemoteCacheIsKeyedPerAcceptHeader(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Header.Get("Accept") {
+		case "text/csv":
+			w.Write([]byte("a,b\n1,2\n"))
+		default:
+			w.Write([]byte(`{"n": 1}`))
+		}
+	}))
+	defer srv.Close()
+
+	ns := newTestNamespace(t, nil)
+
+	v, err := ns.GetJSON(srv.URL)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"n": float64(1)}, v)
+
+	d, err := ns.GetCSV(",", srv.URL)
+	require.NoError(t, err, "the CSV response must not be served from the JSON request's cache entry")
+	assert.Equal(t, [][]string{{"a", "b"}, {"1", "2"}}, d)
+}
@@ -17,20 +17,52 @@ import (
 	"bytes"
 	"encoding/csv"
 	"encoding/json"
+	"encoding/xml"
 	"errors"
 	"fmt"
+	"mime"
 	"net/http"
+	"path"
 	"strings"
 	"time"
 
 	"github.com/gohugoio/hugo/deps"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v2"
+)
+
+// Default retry/backoff/timeout policy for Get* methods, overridable per-site via the
+// data.retries, data.retrySleep, and data.timeout config options.
+const (
+	resRetries = 1
+	resSleep   = 2 * time.Second
+	resTimeout = 30 * time.Second
 )
 
 // New returns a new instance of the data-namespaced template functions.
 func New(deps *deps.Deps) *Namespace {
+	retries := resRetries
+	if deps.Cfg.IsSet("data.retries") {
+		retries = deps.Cfg.GetInt("data.retries")
+	}
+
+	sleep := resSleep
+	if deps.Cfg.IsSet("data.retrySleep") {
+		sleep = deps.Cfg.GetDuration("data.retrySleep")
+	}
+
+	timeout := resTimeout
+	if deps.Cfg.IsSet("data.timeout") {
+		timeout = deps.Cfg.GetDuration("data.timeout")
+	}
+
 	return &Namespace{
-		deps:   deps,
-		client: http.DefaultClient,
+		deps:    deps,
+		client:  &http.Client{Timeout: timeout},
+		retries: retries,
+		sleep:   sleep,
+		remotes: newRemoteConfigs(deps),
 	}
 }
 
@@ -39,6 +71,24 @@ type Namespace struct {
 	deps *deps.Deps
 
 	client *http.Client
+
+	// retries and sleep control how many times, and how long to wait between, a failed fetch or
+	// parse is retried. Configurable via data.retries and data.retrySleep; see resRetries and
+	// resSleep for the defaults.
+	retries int
+	sleep   time.Duration
+
+	// remotes holds the per-host/prefix request policies read from the site's [data.remote]
+	// config table: custom headers, Basic Auth credentials, etc. See getRemote.
+	remotes []remoteConfig
+}
+
+// isStrict reports whether the site is configured with data.strict = true, in which case the
+// data template functions return the real error from a failed fetch or parse instead of logging
+// it and returning nil, nil. This lets a CI build fail loudly on a transient upstream outage
+// rather than publish a site with silently empty data.
+func (ns *Namespace) isStrict() bool {
+	return ns.deps.Cfg.GetBool("data.strict")
 }
 
 // GetCSV expects a data separator and one or n-parts of a URL to a resource which
@@ -49,13 +99,14 @@ type Namespace struct {
 func (ns *Namespace) GetCSV(sep string, urlParts ...string) (d [][]string, err error) {
 	url := strings.Join(urlParts, "")
 
-	var clearCacheSleep = func(i int, u string) {
-		ns.deps.Log.WARN.Printf("Retry #%d for %s and sleeping for %s", i, url, resSleep)
-		time.Sleep(resSleep)
+	var clearCacheSleep = func(i int, u string, req *http.Request) {
+		ns.deps.Log.WARN.Printf("Retry #%d for %s and sleeping for %s", i, url, ns.sleep)
+		time.Sleep(ns.sleep)
 		deleteCache(url, ns.deps.Fs.Source, ns.deps.Cfg)
+		ns.invalidateRemoteCache(url, req.Header.Get("Accept"))
 	}
 
-	for i := 0; i <= resRetries; i++ {
+	for i := 0; i <= ns.retries; i++ {
 		var req *http.Request
 		req, err = http.NewRequest("GET", url, nil)
 		if err != nil {
@@ -66,27 +117,39 @@ func (ns *Namespace) GetCSV(sep string, urlParts ...string) (d [][]string, err e
 		req.Header.Add("Accept", "text/plain")
 
 		var c []byte
-		c, err = ns.getResource(req)
+		c, err = ns.getRemote(req)
 		if err != nil {
-			ns.deps.Log.ERROR.Printf("Failed to read CSV resource %q: %s", url, err)
+			err = fmt.Errorf("%w: failed to read CSV resource %q: %s", ErrDataFetch, url, err)
+			if ns.isStrict() {
+				return nil, err
+			}
+			ns.deps.Log.ERROR.Print(err)
 			return nil, nil
 		}
 
 		if !bytes.Contains(c, []byte(sep)) {
-			ns.deps.Log.ERROR.Printf("Cannot find separator %s in CSV for %s", sep, url)
+			err = fmt.Errorf("%w: cannot find separator %s in CSV for %s", ErrDataSeparator, sep, url)
+			if ns.isStrict() {
+				return nil, err
+			}
+			ns.deps.Log.ERROR.Print(err)
 			return nil, nil
 		}
 
 		if d, err = parseCSV(c, sep); err != nil {
 			ns.deps.Log.WARN.Printf("Failed to parse CSV file %s: %s", url, err)
-			clearCacheSleep(i, url)
+			clearCacheSleep(i, url, req)
 			continue
 		}
 		break
 	}
 
 	if err != nil {
-		ns.deps.Log.ERROR.Printf("Failed to read CSV resource %q: %s", url, err)
+		err = fmt.Errorf("%w: failed to read CSV resource %q: %s", ErrDataParse, url, err)
+		if ns.isStrict() {
+			return nil, err
+		}
+		ns.deps.Log.ERROR.Print(err)
 		return nil, nil
 	}
 
@@ -99,7 +162,7 @@ func (ns *Namespace) GetCSV(sep string, urlParts ...string) (d [][]string, err e
 func (ns *Namespace) GetJSON(urlParts ...string) (v interface{}, err error) {
 	url := strings.Join(urlParts, "")
 
-	for i := 0; i <= resRetries; i++ {
+	for i := 0; i <= ns.retries; i++ {
 		var req *http.Request
 		req, err = http.NewRequest("GET", url, nil)
 		if err != nil {
@@ -109,30 +172,377 @@ func (ns *Namespace) GetJSON(urlParts ...string) (v interface{}, err error) {
 		req.Header.Add("Accept", "application/json")
 
 		var c []byte
-		c, err = ns.getResource(req)
+		c, err = ns.getRemote(req)
 		if err != nil {
-			ns.deps.Log.ERROR.Printf("Failed to get JSON resource %s: %s", url, err)
+			err = fmt.Errorf("%w: failed to get JSON resource %s: %s", ErrDataFetch, url, err)
+			if ns.isStrict() {
+				return nil, err
+			}
+			ns.deps.Log.ERROR.Print(err)
 			return nil, nil
 		}
 
 		err = json.Unmarshal(c, &v)
 		if err != nil {
 			ns.deps.Log.WARN.Printf("Cannot read JSON from resource %s: %s", url, err)
-			ns.deps.Log.WARN.Printf("Retry #%d for %s and sleeping for %s", i, url, resSleep)
-			time.Sleep(resSleep)
+			ns.deps.Log.WARN.Printf("Retry #%d for %s and sleeping for %s", i, url, ns.sleep)
+			time.Sleep(ns.sleep)
 			deleteCache(url, ns.deps.Fs.Source, ns.deps.Cfg)
+			ns.invalidateRemoteCache(url, req.Header.Get("Accept"))
 			continue
 		}
 		break
 	}
 
 	if err != nil {
-		ns.deps.Log.ERROR.Printf("Failed to get JSON resource %s: %s", url, err)
+		err = fmt.Errorf("%w: failed to get JSON resource %s: %s", ErrDataParse, url, err)
+		if ns.isStrict() {
+			return nil, err
+		}
+		ns.deps.Log.ERROR.Print(err)
+		return nil, nil
+	}
+	return
+}
+
+// GetXML expects one or n-parts of a URL to a resource which can either be a local or a remote one.
+// If you provide multiple parts they will be joined together to the final URL.
+// GetXML returns nil or the parsed XML as a tree of map[string]interface{}, with attributes
+// stored under "-name" keys and element text under the "#text" key, to use in a short code.
+func (ns *Namespace) GetXML(urlParts ...string) (v interface{}, err error) {
+	url := strings.Join(urlParts, "")
+
+	for i := 0; i <= ns.retries; i++ {
+		var req *http.Request
+		req, err = http.NewRequest("GET", url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to create request for getXML resource %s: %s", url, err)
+		}
+
+		req.Header.Add("Accept", "application/xml")
+		req.Header.Add("Accept", "text/xml")
+
+		var c []byte
+		c, err = ns.getRemote(req)
+		if err != nil {
+			err = fmt.Errorf("%w: failed to get XML resource %s: %s", ErrDataFetch, url, err)
+			if ns.isStrict() {
+				return nil, err
+			}
+			ns.deps.Log.ERROR.Print(err)
+			return nil, nil
+		}
+
+		v, err = parseXML(c)
+		if err != nil {
+			ns.deps.Log.WARN.Printf("Cannot read XML from resource %s: %s", url, err)
+			ns.deps.Log.WARN.Printf("Retry #%d for %s and sleeping for %s", i, url, ns.sleep)
+			time.Sleep(ns.sleep)
+			deleteCache(url, ns.deps.Fs.Source, ns.deps.Cfg)
+			ns.invalidateRemoteCache(url, req.Header.Get("Accept"))
+			continue
+		}
+		break
+	}
+
+	if err != nil {
+		err = fmt.Errorf("%w: failed to get XML resource %s: %s", ErrDataParse, url, err)
+		if ns.isStrict() {
+			return nil, err
+		}
+		ns.deps.Log.ERROR.Print(err)
+		return nil, nil
+	}
+	return
+}
+
+// GetData expects one or n-parts of a URL to a resource which can either be a local or a remote
+// one. If you provide multiple parts they will be joined together to the final URL. GetData
+// determines the format of the resource from its file extension and, failing that, fetches it once
+// and determines the format from the response Content-Type or, failing that, by sniffing the
+// content, then dispatches to GetCSV, GetJSON, GetXML, or the YAML/TOML equivalents.
+func (ns *Namespace) GetData(urlParts ...string) (v interface{}, err error) {
+	url := strings.Join(urlParts, "")
+
+	switch resourceFormat(url) {
+	case "json":
+		return ns.GetJSON(urlParts...)
+	case "xml":
+		return ns.GetXML(urlParts...)
+	case "csv":
+		return ns.GetCSV(",", urlParts...)
+	case "yaml":
+		return ns.getYAML(urlParts...)
+	case "toml":
+		return ns.getTOML(urlParts...)
+	}
+
+	// No recognizable extension, e.g. an API endpoint with no suffix in its path.
+	// Fetch once and let the response Content-Type settle it; failing that, sniff the content.
+	var req *http.Request
+	req, err = http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create request for getData resource %s: %s", url, err)
+	}
+
+	var c []byte
+	var header http.Header
+	c, header, err = ns.getRemoteHeader(req)
+	if err != nil {
+		err = fmt.Errorf("%w: failed to get data resource %s: %s", ErrDataFetch, url, err)
+		if ns.isStrict() {
+			return nil, err
+		}
+		ns.deps.Log.ERROR.Print(err)
+		return nil, nil
+	}
+
+	format := contentTypeFormat(header.Get("Content-Type"))
+	if format == "" {
+		format = sniffFormat(c)
+	}
+
+	switch format {
+	case "xml":
+		return parseXML(c)
+	case "json":
+		err = json.Unmarshal(c, &v)
+	case "csv":
+		v, err = parseCSV(c, ",")
+	case "yaml":
+		var y interface{}
+		if err = yaml.Unmarshal(c, &y); err == nil {
+			v = normalizeYAML(y)
+		}
+	case "toml":
+		err = toml.Unmarshal(c, &v)
+	default:
+		err = fmt.Errorf("could not determine the data format of resource %s", url)
+	}
+
+	if err != nil {
+		err = fmt.Errorf("%w: failed to parse data resource %s: %s", ErrDataParse, url, err)
+		if ns.isStrict() {
+			return nil, err
+		}
+		ns.deps.Log.ERROR.Print(err)
 		return nil, nil
 	}
 	return
 }
 
+// contentTypeFormat maps a response Content-Type header to one of the data formats GetData
+// understands, or "" if the media type is unset or not recognized. Parameters such as
+// "; charset=utf-8" are ignored.
+func contentTypeFormat(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+
+	switch mediaType {
+	case "application/json", "text/json":
+		return "json"
+	case "application/xml", "text/xml":
+		return "xml"
+	case "text/csv":
+		return "csv"
+	case "application/yaml", "text/yaml", "application/x-yaml", "text/x-yaml":
+		return "yaml"
+	case "application/toml", "text/toml":
+		return "toml"
+	default:
+		return ""
+	}
+}
+
+// getYAML is the YAML counterpart of GetJSON, used by GetData to resolve resources ending in
+// ".yaml" or ".yml".
+func (ns *Namespace) getYAML(urlParts ...string) (v interface{}, err error) {
+	url := strings.Join(urlParts, "")
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create request for getYAML resource %s: %s", url, err)
+	}
+	req.Header.Add("Accept", "application/yaml")
+
+	c, err := ns.getRemote(req)
+	if err != nil {
+		err = fmt.Errorf("%w: failed to get YAML resource %s: %s", ErrDataFetch, url, err)
+		if ns.isStrict() {
+			return nil, err
+		}
+		ns.deps.Log.ERROR.Print(err)
+		return nil, nil
+	}
+
+	var y interface{}
+	if err = yaml.Unmarshal(c, &y); err != nil {
+		err = fmt.Errorf("%w: cannot read YAML from resource %s: %s", ErrDataParse, url, err)
+		if ns.isStrict() {
+			return nil, err
+		}
+		ns.deps.Log.ERROR.Print(err)
+		return nil, nil
+	}
+	return normalizeYAML(y), nil
+}
+
+// getTOML is the TOML counterpart of GetJSON, used by GetData to resolve resources ending in
+// ".toml".
+func (ns *Namespace) getTOML(urlParts ...string) (v interface{}, err error) {
+	url := strings.Join(urlParts, "")
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create request for getTOML resource %s: %s", url, err)
+	}
+	req.Header.Add("Accept", "application/toml")
+
+	c, err := ns.getRemote(req)
+	if err != nil {
+		err = fmt.Errorf("%w: failed to get TOML resource %s: %s", ErrDataFetch, url, err)
+		if ns.isStrict() {
+			return nil, err
+		}
+		ns.deps.Log.ERROR.Print(err)
+		return nil, nil
+	}
+
+	var t interface{}
+	if err = toml.Unmarshal(c, &t); err != nil {
+		err = fmt.Errorf("%w: cannot read TOML from resource %s: %s", ErrDataParse, url, err)
+		if ns.isStrict() {
+			return nil, err
+		}
+		ns.deps.Log.ERROR.Print(err)
+		return nil, nil
+	}
+	return t, nil
+}
+
+// resourceFormat returns the data format implied by url's file extension, or the empty string
+// if the extension is missing or unrecognized.
+func resourceFormat(url string) string {
+	switch strings.ToLower(path.Ext(strings.SplitN(url, "?", 2)[0])) {
+	case ".json":
+		return "json"
+	case ".xml":
+		return "xml"
+	case ".csv":
+		return "csv"
+	case ".yaml", ".yml":
+		return "yaml"
+	case ".toml":
+		return "toml"
+	default:
+		return ""
+	}
+}
+
+// sniffFormat makes a best-effort guess at the data format of c by inspecting its first
+// non-whitespace byte, for resources whose URL carries no useful extension.
+func sniffFormat(c []byte) string {
+	trimmed := bytes.TrimLeft(c, " \t\r\n")
+	if len(trimmed) == 0 {
+		return ""
+	}
+	switch trimmed[0] {
+	case '<':
+		return "xml"
+	case '{', '[':
+		return "json"
+	default:
+		return ""
+	}
+}
+
+// normalizeYAML recursively converts the map[interface{}]interface{} values produced by
+// yaml.Unmarshal into map[string]interface{}, so YAML data can be indexed the same way as JSON
+// data in templates.
+func normalizeYAML(i interface{}) interface{} {
+	switch x := i.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(x))
+		for k, v := range x {
+			m[fmt.Sprintf("%v", k)] = normalizeYAML(v)
+		}
+		return m
+	case []interface{}:
+		for i, v := range x {
+			x[i] = normalizeYAML(v)
+		}
+		return x
+	default:
+		return i
+	}
+}
+
+// parseXML parses bytes of XML data into a tree of map[string]interface{}, or an error.
+// Attributes are stored under "-name" keys, and element text content under the "#text" key.
+// Repeated child elements with the same name are collected into a []interface{}.
+func parseXML(c []byte) (interface{}, error) {
+	d := xml.NewDecoder(bytes.NewReader(c))
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+		if se, ok := tok.(xml.StartElement); ok {
+			return parseXMLElement(d, se)
+		}
+	}
+}
+
+// parseXMLElement decodes the children of start, assuming d has just emitted start, and returns
+// once it has consumed the matching EndElement.
+func parseXMLElement(d *xml.Decoder, start xml.StartElement) (map[string]interface{}, error) {
+	node := make(map[string]interface{})
+	for _, attr := range start.Attr {
+		node["-"+attr.Name.Local] = attr.Value
+	}
+
+	var text bytes.Buffer
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			child, err := parseXMLElement(d, t)
+			if err != nil {
+				return nil, err
+			}
+			addXMLChild(node, t.Name.Local, child)
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			if s := strings.TrimSpace(text.String()); s != "" {
+				node["#text"] = s
+			}
+			return node, nil
+		}
+	}
+}
+
+// addXMLChild adds child under name in node, turning the value into a []interface{} the second
+// and subsequent times the same element name is seen, mirroring how other XML-to-map conventions
+// (e.g. Go's encoding/xml to JSON bridges) represent repeated elements.
+func addXMLChild(node map[string]interface{}, name string, child interface{}) {
+	existing, ok := node[name]
+	if !ok {
+		node[name] = child
+		return
+	}
+	if list, ok := existing.([]interface{}); ok {
+		node[name] = append(list, child)
+		return
+	}
+	node[name] = []interface{}{existing, child}
+}
+
 // parseCSV parses bytes of CSV data into a slice slice string or an error
 func parseCSV(c []byte, sep string) ([][]string, error) {
 	if len(sep) != 1 {
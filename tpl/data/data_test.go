@@ -0,0 +1,257 @@
+// Copyright 2017 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package data
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gohugoio/hugo/deps"
+	"github.com/gohugoio/hugo/hugofs"
+	"github.com/gohugoio/hugo/loggers"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestNamespace builds a Namespace whose deps.Cfg can be customized by configure, which may be
+// nil. It is shared by every test file in this package.
+func newTestNamespace(t *testing.T, configure func(cfg *viper.Viper)) *Namespace {
+	v := viper.New()
+	if configure != nil {
+		configure(v)
+	}
+
+	return New(testDeps(t, v))
+}
+
+// testDeps builds the *deps.Deps backing newTestNamespace, for tests that need it directly, e.g.
+// to call newRemoteConfigs on its own.
+func testDeps(t *testing.T, v *viper.Viper) *deps.Deps {
+	return &deps.Deps{
+		Cfg: v,
+		Fs:  hugofs.NewMem(v),
+		Log: loggers.NewErrorLogger(),
+	}
+}
+
+func TestGetJSONNonStrictSwallowsFetchError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	ns := newTestNamespace(t, nil)
+
+	v, err := ns.GetJSON(srv.URL)
+	require.NoError(t, err)
+	assert.Nil(t, v)
+}
+
+func TestGetJSONStrictReturnsFetchError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	ns := newTestNamespace(t, func(cfg *viper.Viper) {
+		cfg.Set("data.strict", true)
+	})
+
+	v, err := ns.GetJSON(srv.URL)
+	assert.Nil(t, v)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrDataFetch))
+}
+
+func TestGetJSONStrictReturnsParseError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("{not valid json"))
+	}))
+	defer srv.Close()
+
+	ns := newTestNamespace(t, func(cfg *viper.Viper) {
+		cfg.Set("data.strict", true)
+		cfg.Set("data.retries", 0)
+	})
+
+	v, err := ns.GetJSON(srv.URL)
+	assert.Nil(t, v)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrDataParse))
+}
+
+func TestParseXML(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		xml  string
+		want interface{}
+	}{
+		{
+			name: "attribute and text",
+			xml:  `<book id="42">Hugo in Action</book>`,
+			want: map[string]interface{}{"-id": "42", "#text": "Hugo in Action"},
+		},
+		{
+			name: "nested element",
+			xml:  `<book><title>Hugo in Action</title></book>`,
+			want: map[string]interface{}{"title": map[string]interface{}{"#text": "Hugo in Action"}},
+		},
+		{
+			name: "repeated siblings collapse into a slice",
+			xml:  `<shelf><book>A</book><book>B</book><book>C</book></shelf>`,
+			want: map[string]interface{}{"book": []interface{}{
+				map[string]interface{}{"#text": "A"},
+				map[string]interface{}{"#text": "B"},
+				map[string]interface{}{"#text": "C"},
+			}},
+		},
+		{
+			name: "element with no text or attributes",
+			xml:  `<empty></empty>`,
+			want: map[string]interface{}{},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := parseXML([]byte(tt.xml))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, v)
+		})
+	}
+}
+
+func TestParseXMLReturnsErrorOnMalformedInput(t *testing.T) {
+	_, err := parseXML([]byte(`<book>`))
+	require.Error(t, err)
+}
+
+func TestGetXMLParsesAttributesAndText(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<book id="42">Hugo in Action</book>`))
+	}))
+	defer srv.Close()
+
+	ns := newTestNamespace(t, nil)
+
+	v, err := ns.GetXML(srv.URL)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"-id": "42", "#text": "Hugo in Action"}, v)
+}
+
+func TestGetXMLStrictReturnsFetchError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	ns := newTestNamespace(t, func(cfg *viper.Viper) {
+		cfg.Set("data.strict", true)
+	})
+
+	v, err := ns.GetXML(srv.URL)
+	assert.Nil(t, v)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrDataFetch))
+}
+
+func TestGetXMLStrictReturnsParseError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<not valid xml"))
+	}))
+	defer srv.Close()
+
+	ns := newTestNamespace(t, func(cfg *viper.Viper) {
+		cfg.Set("data.strict", true)
+		cfg.Set("data.retries", 0)
+	})
+
+	v, err := ns.GetXML(srv.URL)
+	assert.Nil(t, v)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrDataParse))
+}
+
+func TestGetDataDispatchesByExtension(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"n": 1}`))
+	}))
+	defer srv.Close()
+
+	ns := newTestNamespace(t, nil)
+
+	v, err := ns.GetData(srv.URL + "/data.json")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"n": float64(1)}, v)
+}
+
+func TestGetDataUsesContentTypeWhenExtensionless(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Write([]byte("a,b\n1,2\n"))
+	}))
+	defer srv.Close()
+
+	ns := newTestNamespace(t, nil)
+
+	v, err := ns.GetData(srv.URL)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"a", "b"}, {"1", "2"}}, v)
+}
+
+func TestGetDataSniffsFormatWhenContentTypeIsUnrecognized(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// No Content-Type set: Go's ResponseWriter will sniff and send a generic
+		// text/plain, which GetData doesn't recognize either, forcing it down to its
+		// own byte-sniffing fallback.
+		w.Write([]byte(`{"n": 1}`))
+	}))
+	defer srv.Close()
+
+	ns := newTestNamespace(t, nil)
+
+	v, err := ns.GetData(srv.URL)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"n": float64(1)}, v)
+}
+
+func TestGetDataNonStrictReturnsNilWhenFormatCannotBeDetermined(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("plain text, not any recognized data format"))
+	}))
+	defer srv.Close()
+
+	ns := newTestNamespace(t, nil)
+
+	v, err := ns.GetData(srv.URL)
+	require.NoError(t, err)
+	assert.Nil(t, v)
+}
+
+func TestGetDataStrictReturnsErrorWhenFormatCannotBeDetermined(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("plain text, not any recognized data format"))
+	}))
+	defer srv.Close()
+
+	ns := newTestNamespace(t, func(cfg *viper.Viper) {
+		cfg.Set("data.strict", true)
+	})
+
+	v, err := ns.GetData(srv.URL)
+	assert.Nil(t, v)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrDataParse))
+}
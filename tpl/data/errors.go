@@ -0,0 +1,30 @@
+// Copyright 2017 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package data
+
+import "errors"
+
+// Sentinel errors returned by the data template functions when running in strict mode (see
+// Namespace.isStrict). Wrap one of these with fmt.Errorf's %w verb so callers can tell them apart
+// with errors.Is, without depending on the exact wording of the underlying failure.
+var (
+	// ErrDataFetch is returned when a resource, local or remote, could not be retrieved.
+	ErrDataFetch = errors.New("failed to fetch data resource")
+
+	// ErrDataParse is returned when a fetched resource could not be parsed as the expected format.
+	ErrDataParse = errors.New("failed to parse data resource")
+
+	// ErrDataSeparator is returned when GetCSV is given an invalid or missing field separator.
+	ErrDataSeparator = errors.New("invalid data separator")
+)
@@ -0,0 +1,132 @@
+// Copyright 2017 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// maxPaginatedPages caps how many pages GetJSONPaged and GetJSONPagedLink will follow, so a
+// misbehaving API that never stops advertising a next page can't hang a build.
+const maxPaginatedPages = 100
+
+// GetJSONPaged fetches url and every subsequent page reachable through it, following the
+// next-page URL found under nextField in each decoded JSON object, and concatenates the arrays
+// found under itemsField across all pages into a single []interface{}. Pagination stops once a
+// page's nextField is missing or empty.
+//
+// Pages are fetched through getRemote directly rather than through GetJSON: GetJSON's non-strict
+// default swallows a fetch or parse failure as (nil, nil), which would otherwise surface here as a
+// misleading "page is not a JSON object" error instead of the real, possibly transient, cause.
+func (ns *Namespace) GetJSONPaged(url, nextField, itemsField string) ([]interface{}, error) {
+	var all []interface{}
+
+	for page := 0; url != ""; page++ {
+		if page >= maxPaginatedPages {
+			return nil, fmt.Errorf("%w: %s exceeded %d pages", ErrDataFetch, url, maxPaginatedPages)
+		}
+
+		req, err := http.NewRequest("GET", url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to create request for GetJSONPaged resource %s: %s", url, err)
+		}
+		req.Header.Add("Accept", "application/json")
+
+		c, err := ns.getRemote(req)
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to get page %s: %s", ErrDataFetch, url, err)
+		}
+
+		var v interface{}
+		if err := json.Unmarshal(c, &v); err != nil {
+			return nil, fmt.Errorf("%w: failed to decode page %s: %s", ErrDataParse, url, err)
+		}
+
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%w: page %s is not a JSON object", ErrDataParse, url)
+		}
+
+		if items, ok := m[itemsField].([]interface{}); ok {
+			all = append(all, items...)
+		}
+
+		next, _ := m[nextField].(string)
+		url = next
+	}
+
+	return all, nil
+}
+
+// GetJSONPagedLink is the GitHub-style counterpart of GetJSONPaged: it expects every page's body
+// to itself be a top-level JSON array, and finds the next page from the response's
+// `Link: <url>; rel="next"` header rather than a field inside the body.
+//
+// Like GetJSONPaged, pages are fetched through getRemoteHeader rather than GetJSON, both to avoid
+// GetJSON's non-strict error-swallowing and to get the on-disk conditional-GET cache for free
+// instead of re-fetching every page on every build.
+func (ns *Namespace) GetJSONPagedLink(url string) ([]interface{}, error) {
+	var all []interface{}
+
+	for page := 0; url != ""; page++ {
+		if page >= maxPaginatedPages {
+			return nil, fmt.Errorf("%w: %s exceeded %d pages", ErrDataFetch, url, maxPaginatedPages)
+		}
+
+		req, err := http.NewRequest("GET", url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to create request for GetJSONPagedLink resource %s: %s", url, err)
+		}
+		req.Header.Add("Accept", "application/json")
+
+		c, header, err := ns.getRemoteHeader(req)
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to fetch page %s: %s", ErrDataFetch, url, err)
+		}
+
+		var items []interface{}
+		if err := json.Unmarshal(c, &items); err != nil {
+			return nil, fmt.Errorf("%w: failed to decode JSON array from %s: %s", ErrDataParse, url, err)
+		}
+
+		all = append(all, items...)
+		url = parseNextLink(header.Get("Link"))
+	}
+
+	return all, nil
+}
+
+// parseNextLink parses an RFC 5988 Link header value such as
+// `<https://api.example.com/items?page=2>; rel="next", <https://api.example.com/items?page=9>; rel="last"`
+// and returns the URL tagged rel="next", or "" if there is none.
+func parseNextLink(header string) string {
+	for _, part := range strings.Split(header, ",") {
+		segments := strings.Split(part, ";")
+		if len(segments) < 2 {
+			continue
+		}
+
+		url := strings.Trim(strings.TrimSpace(segments[0]), "<>")
+		for _, seg := range segments[1:] {
+			seg = strings.TrimSpace(seg)
+			if seg == `rel="next"` || seg == "rel=next" {
+				return url
+			}
+		}
+	}
+	return ""
+}
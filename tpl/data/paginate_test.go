@@ -0,0 +1,96 @@
+// Copyright 2017 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package data
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetJSONPagedFollowsNextField(t *testing.T) {
+	const pageCount = 3
+
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		if page == "" {
+			page = "0"
+		}
+
+		next := ""
+		if page != fmt.Sprint(pageCount-1) {
+			var n int
+			fmt.Sscanf(page, "%d", &n)
+			next = fmt.Sprintf("%s?page=%d", srv.URL, n+1)
+		}
+
+		fmt.Fprintf(w, `{"items": [%q], "next": %q}`, "item-"+page, next)
+	}))
+	defer srv.Close()
+
+	ns := newTestNamespace(t, nil)
+
+	items, err := ns.GetJSONPaged(srv.URL, "next", "items")
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"item-0", "item-1", "item-2"}, items)
+}
+
+func TestGetJSONPagedStopsAtPageLimit(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Always points back at itself: a misbehaving API that never stops paginating.
+		fmt.Fprintf(w, `{"items": [], "next": %q}`, srv.URL)
+	}))
+	defer srv.Close()
+
+	ns := newTestNamespace(t, nil)
+
+	_, err := ns.GetJSONPaged(srv.URL, "next", "items")
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrDataFetch))
+}
+
+func TestGetJSONPagedRejectsNonObjectPage(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[1, 2, 3]`))
+	}))
+	defer srv.Close()
+
+	ns := newTestNamespace(t, nil)
+
+	_, err := ns.GetJSONPaged(srv.URL, "next", "items")
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrDataParse))
+}
+
+func TestParseNextLinkFindsRelNext(t *testing.T) {
+	header := `<https://api.example.com/items?page=2>; rel="next", <https://api.example.com/items?page=9>; rel="last"`
+	assert.Equal(t, "https://api.example.com/items?page=2", parseNextLink(header))
+}
+
+func TestParseNextLinkReturnsEmptyWithoutNext(t *testing.T) {
+	for _, header := range []string{
+		"",
+		`<https://api.example.com/items?page=9>; rel="last"`,
+		"garbage, that doesn't parse as a link header at all",
+	} {
+		assert.Equal(t, "", parseNextLink(header))
+	}
+}
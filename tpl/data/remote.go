@@ -0,0 +1,174 @@
+// Copyright 2017 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package data
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cast"
+
+	"github.com/gohugoio/hugo/deps"
+)
+
+// remoteConfig is one entry of the site's [[data.remote]] config table, giving a request policy
+// (custom headers, Basic Auth, cache freshness) to apply to any URL starting with Prefix. Entries
+// are matched by the longest-matching Prefix first, so a site can set a default for a whole host
+// and override it for a specific path underneath.
+type remoteConfig struct {
+	Prefix   string
+	Headers  map[string]string
+	Username string
+	Password string
+
+	// CacheTTL overrides defaultRemoteCacheTTL for URLs matching Prefix; see cacheTTL.
+	CacheTTL time.Duration
+}
+
+// newRemoteConfigs reads the [[data.remote]] config table into a slice of remoteConfig, longest
+// Prefix first. It returns nil if the site sets no such config. Each entry is decoded leniently
+// with cast, rather than via a single concrete-type assertion on the whole table, because config
+// providers normalize TOML/YAML/JSON table arrays to different Go shapes (e.g. []map[string]interface{}
+// from TOML vs. []interface{} of map[string]interface{} from YAML/JSON); if deps is non-nil and the
+// table still can't be made sense of despite data.remote being set, a warning is logged so a
+// misconfigured site doesn't silently lose every header/auth/cache override.
+func newRemoteConfigs(deps *deps.Deps) []remoteConfig {
+	raw := cast.ToSlice(deps.Cfg.Get("data.remote"))
+	if raw == nil {
+		if deps.Cfg.IsSet("data.remote") {
+			deps.Log.WARN.Printf("data.remote is set but could not be read as a table array; ignoring it")
+		}
+		return nil
+	}
+
+	configs := make([]remoteConfig, 0, len(raw))
+	for _, e := range raw {
+		m := cast.ToStringMap(e)
+		if m == nil {
+			deps.Log.WARN.Printf("Skipping unreadable data.remote entry: %v", e)
+			continue
+		}
+
+		rc := remoteConfig{
+			Prefix:   cast.ToString(m["prefix"]),
+			Username: cast.ToString(m["username"]),
+			Password: cast.ToString(m["password"]),
+			CacheTTL: cast.ToDuration(m["cacheTTL"]),
+		}
+		if headers := cast.ToStringMapString(m["headers"]); headers != nil {
+			rc.Headers = headers
+		}
+		if rc.Prefix != "" {
+			configs = append(configs, rc)
+		}
+	}
+
+	sort.Slice(configs, func(i, j int) bool {
+		return len(configs[i].Prefix) > len(configs[j].Prefix)
+	})
+
+	return configs
+}
+
+// configFor returns the most specific remoteConfig whose Prefix matches url, or nil if none do.
+func (ns *Namespace) configFor(url string) *remoteConfig {
+	for i, rc := range ns.remotes {
+		if strings.HasPrefix(url, rc.Prefix) {
+			return &ns.remotes[i]
+		}
+	}
+	return nil
+}
+
+// getRemote is the shared entry point used by GetCSV, GetJSON, GetXML, GetData and their YAML/TOML
+// counterparts to fetch a resource. Before delegating to getResource it applies any matching
+// [[data.remote]] policy: custom headers and Basic Auth credentials keyed by URL prefix.
+//
+// For http(s) URLs it also maintains an on-disk cache of the response body alongside its ETag and
+// Last-Modified headers (see cache.go). A fresh cache entry (within cacheTTL) is served without a
+// request at all; a stale one is revalidated with If-None-Match/If-Modified-Since, and a 304
+// response is treated as a cache hit. hugo --ignoreCache forces revalidation of every entry but,
+// unlike a cold cache, still sends the stored validators so an unchanged upstream costs only a 304.
+func (ns *Namespace) getRemote(req *http.Request) ([]byte, error) {
+	body, _, err := ns.getRemoteHeader(req)
+	return body, err
+}
+
+// getRemoteHeader is getRemote's header-returning counterpart, for callers such as GetData that
+// need the response's Content-Type to negotiate a format. The header is that of the response that
+// produced body: the freshly-fetched response header on a miss or revalidation, or the cached
+// header on a TTL or 304 hit.
+func (ns *Namespace) getRemoteHeader(req *http.Request) ([]byte, http.Header, error) {
+	if rc := ns.configFor(req.URL.String()); rc != nil {
+		for k, v := range rc.Headers {
+			req.Header.Set(k, v)
+		}
+		if rc.Username != "" {
+			req.SetBasicAuth(rc.Username, rc.Password)
+		}
+	}
+
+	if req.Method != "GET" || (req.URL.Scheme != "http" && req.URL.Scheme != "https") {
+		body, err := ns.getResource(req)
+		return body, nil, err
+	}
+
+	url := req.URL.String()
+	accept := req.Header.Get("Accept")
+	ignoreCache := ns.deps.Cfg.GetBool("ignoreCache")
+
+	entry, hasEntry := ns.readRemoteCache(url, accept)
+	if hasEntry && !ignoreCache && time.Since(entry.FetchedAt) < ns.cacheTTL(url) {
+		return entry.Body, entry.Header, nil
+	}
+	if hasEntry {
+		if etag := entry.Header.Get("Etag"); etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		if lastMod := entry.Header.Get("Last-Modified"); lastMod != "" {
+			req.Header.Set("If-Modified-Since", lastMod)
+		}
+	}
+
+	resp, err := ns.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && hasEntry {
+		entry.FetchedAt = time.Now()
+		_ = ns.writeRemoteCache(url, accept, entry)
+		return entry.Body, entry.Header, nil
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, nil, fmt.Errorf("failed to retrieve remote resource %s: status %s", url, resp.Status)
+	}
+
+	newEntry := &remoteCacheEntry{Body: body, Header: resp.Header, FetchedAt: time.Now()}
+	if err := ns.writeRemoteCache(url, accept, newEntry); err != nil {
+		ns.deps.Log.WARN.Printf("Failed to write data cache entry for %s: %s", url, err)
+	}
+
+	return body, resp.Header, nil
+}
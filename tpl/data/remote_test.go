@@ -0,0 +1,113 @@
+// Copyright 2017 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package data
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetJSONAppliesConfiguredHeadersAndBasicAuth(t *testing.T) {
+	var gotHeader, gotUser, gotPass string
+	var gotOK bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header.Get("X-Api-Key")
+		gotUser, gotPass, gotOK = r.BasicAuth()
+		w.Write([]byte(`{"n": 1}`))
+	}))
+	defer srv.Close()
+
+	ns := newTestNamespace(t, func(cfg *viper.Viper) {
+		cfg.Set("data.remote", []map[string]interface{}{
+			{
+				"prefix":   srv.URL,
+				"headers":  map[string]interface{}{"X-Api-Key": "secret"},
+				"username": "alice",
+				"password": "hunter2",
+			},
+		})
+	})
+
+	_, err := ns.GetJSON(srv.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", gotHeader)
+	assert.True(t, gotOK)
+	assert.Equal(t, "alice", gotUser)
+	assert.Equal(t, "hunter2", gotPass)
+}
+
+func TestNewRemoteConfigsAcceptsSliceOfMapInterfaceShape(t *testing.T) {
+	// This is the shape a YAML- or JSON-sourced config provider tends to produce for a table
+	// array, as opposed to the []map[string]interface{} shape TOML produces.
+	v := viper.New()
+	v.Set("data.remote", []interface{}{
+		map[string]interface{}{
+			"prefix":  "https://api.example.com/",
+			"headers": map[string]interface{}{"Accept-Language": "en"},
+		},
+	})
+
+	configs := newRemoteConfigs(testDeps(t, v))
+	require.Len(t, configs, 1)
+	assert.Equal(t, "https://api.example.com/", configs[0].Prefix)
+	assert.Equal(t, "en", configs[0].Headers["Accept-Language"])
+}
+
+func TestNewRemoteConfigsSkipsUnreadableEntriesWithoutPanicking(t *testing.T) {
+	v := viper.New()
+	v.Set("data.remote", []interface{}{"not-a-map", 42, nil})
+
+	assert.NotPanics(t, func() {
+		configs := newRemoteConfigs(testDeps(t, v))
+		assert.Empty(t, configs)
+	})
+}
+
+func TestNewRemoteConfigsReturnsNilWhenUnset(t *testing.T) {
+	v := viper.New()
+	assert.Nil(t, newRemoteConfigs(testDeps(t, v)))
+}
+
+func TestConfigForMatchesLongestPrefix(t *testing.T) {
+	v := viper.New()
+	v.Set("data.remote", []map[string]interface{}{
+		{"prefix": "https://api.example.com/", "headers": map[string]interface{}{"X-Scope": "host"}},
+		{"prefix": "https://api.example.com/v2/", "headers": map[string]interface{}{"X-Scope": "v2"}},
+	})
+
+	ns := New(testDeps(t, v))
+
+	rc := ns.configFor("https://api.example.com/v2/widgets")
+	require.NotNil(t, rc)
+	assert.Equal(t, "v2", rc.Headers["X-Scope"])
+
+	rc = ns.configFor("https://api.example.com/widgets")
+	require.NotNil(t, rc)
+	assert.Equal(t, "host", rc.Headers["X-Scope"])
+}
+
+func TestConfigForReturnsNilWhenNoPrefixMatches(t *testing.T) {
+	v := viper.New()
+	v.Set("data.remote", []map[string]interface{}{
+		{"prefix": "https://api.example.com/"},
+	})
+
+	ns := New(testDeps(t, v))
+	assert.Nil(t, ns.configFor("https://other.example.com/widgets"))
+}
@@ -0,0 +1,152 @@
+// Copyright 2017 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package data
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// openStream issues req directly through ns.client, applying any matching [[data.remote]] policy,
+// but deliberately bypassing the getRemote response cache: the streaming functions below decode
+// the body as it arrives and never hold the whole thing in memory, so there is nothing complete to
+// cache. For a local resource (non-GET or non-http(s), same as getRemoteHeader) it reads the file
+// via getResource instead, since there is no response to stream from ns.client. Callers must close
+// the returned io.ReadCloser.
+func (ns *Namespace) openStream(req *http.Request) (io.ReadCloser, error) {
+	if rc := ns.configFor(req.URL.String()); rc != nil {
+		for k, v := range rc.Headers {
+			req.Header.Set(k, v)
+		}
+		if rc.Username != "" {
+			req.SetBasicAuth(rc.Username, rc.Password)
+		}
+	}
+
+	if req.Method != "GET" || (req.URL.Scheme != "http" && req.URL.Scheme != "https") {
+		body, err := ns.getResource(req)
+		if err != nil {
+			return nil, err
+		}
+		return ioutil.NopCloser(bytes.NewReader(body)), nil
+	}
+
+	resp, err := ns.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to open stream for %s: %s", ErrDataFetch, req.URL, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%w: failed to open stream for %s: status %s", ErrDataFetch, req.URL, resp.Status)
+	}
+	return resp.Body, nil
+}
+
+// RangeJSON decodes the top-level JSON array at the resource named by urlParts one element at a
+// time, calling fn for each. It stops and returns fn's error as soon as fn returns one, so a
+// template-facing caller can bail out of a multi-megabyte feed without decoding the rest of it.
+func (ns *Namespace) RangeJSON(fn func(v interface{}) error, urlParts ...string) error {
+	url := strings.Join(urlParts, "")
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("Failed to create request for RangeJSON resource %s: %s", url, err)
+	}
+	req.Header.Add("Accept", "application/json")
+
+	body, err := ns.openStream(req)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	dec := json.NewDecoder(body)
+
+	if tok, err := dec.Token(); err != nil {
+		return fmt.Errorf("%w: failed to read JSON array from %s: %s", ErrDataParse, url, err)
+	} else if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("%w: resource %s is not a top-level JSON array", ErrDataParse, url)
+	}
+
+	for dec.More() {
+		var v interface{}
+		if err := dec.Decode(&v); err != nil {
+			return fmt.Errorf("%w: failed to decode JSON element from %s: %s", ErrDataParse, url, err)
+		}
+		if err := fn(v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetJSONStream is the slice-returning counterpart of RangeJSON, for templates that want every
+// element of a large JSON array without holding the raw response body in memory at once.
+func (ns *Namespace) GetJSONStream(urlParts ...string) ([]interface{}, error) {
+	var vs []interface{}
+	err := ns.RangeJSON(func(v interface{}) error {
+		vs = append(vs, v)
+		return nil
+	}, urlParts...)
+	if err != nil {
+		return nil, err
+	}
+	return vs, nil
+}
+
+// RangeCSV decodes the CSV resource named by urlParts one record at a time, calling fn for each
+// row. It stops and returns fn's error as soon as fn returns one.
+func (ns *Namespace) RangeCSV(sep string, fn func(record []string) error, urlParts ...string) error {
+	if len(sep) != 1 {
+		return fmt.Errorf("%w: %s", ErrDataSeparator, sep)
+	}
+	url := strings.Join(urlParts, "")
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("Failed to create request for RangeCSV resource %s: %s", url, err)
+	}
+	req.Header.Add("Accept", "text/csv")
+	req.Header.Add("Accept", "text/plain")
+
+	body, err := ns.openStream(req)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	r := csv.NewReader(body)
+	r.Comma = []rune(sep)[0]
+	r.FieldsPerRecord = 0
+
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%w: failed to decode CSV record from %s: %s", ErrDataParse, url, err)
+		}
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+}
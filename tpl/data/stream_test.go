@@ -0,0 +1,110 @@
+// Copyright 2017 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package data
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRangeJSONVisitsEachElement(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[1, 2, 3]`))
+	}))
+	defer srv.Close()
+
+	ns := newTestNamespace(t, nil)
+
+	var got []interface{}
+	err := ns.RangeJSON(func(v interface{}) error {
+		got = append(got, v)
+		return nil
+	}, srv.URL)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{1.0, 2.0, 3.0}, got)
+}
+
+func TestRangeJSONStopsEarlyOnCallbackError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[1, 2, 3]`))
+	}))
+	defer srv.Close()
+
+	ns := newTestNamespace(t, nil)
+
+	errStop := errors.New("stop")
+	var seen int
+	err := ns.RangeJSON(func(v interface{}) error {
+		seen++
+		if seen == 2 {
+			return errStop
+		}
+		return nil
+	}, srv.URL)
+	assert.Same(t, errStop, err)
+	assert.Equal(t, 2, seen)
+}
+
+func TestRangeJSONRejectsNonArrayTopLevel(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"n": 1}`))
+	}))
+	defer srv.Close()
+
+	ns := newTestNamespace(t, nil)
+
+	err := ns.RangeJSON(func(v interface{}) error { return nil }, srv.URL)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrDataParse))
+}
+
+func TestRangeCSVVisitsEachRecord(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("a,b\n1,2\n3,4\n"))
+	}))
+	defer srv.Close()
+
+	ns := newTestNamespace(t, nil)
+
+	var got [][]string
+	err := ns.RangeCSV(",", func(record []string) error {
+		got = append(got, record)
+		return nil
+	}, srv.URL)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"a", "b"}, {"1", "2"}, {"3", "4"}}, got)
+}
+
+func TestRangeJSONFallsBackToLocalResourceForNonHTTPURL(t *testing.T) {
+	ns := newTestNamespace(t, nil)
+
+	err := ns.RangeJSON(func(v interface{}) error { return nil }, "testdata/nonexistent.json")
+	require.Error(t, err)
+	// Before the local-file fallback, a non-http(s) URL reached ns.client.Do and failed with
+	// "unsupported protocol scheme"; it should now fail as a local resource lookup instead.
+	assert.NotContains(t, err.Error(), "unsupported protocol scheme")
+}
+
+func TestRangeCSVFallsBackToLocalResourceForNonHTTPURL(t *testing.T) {
+	ns := newTestNamespace(t, nil)
+
+	err := ns.RangeCSV(",", func(record []string) error { return nil }, "testdata/nonexistent.csv")
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "unsupported protocol scheme")
+}